@@ -0,0 +1,75 @@
+// Package bybit is a skeleton exchange.Exchange implementation for Bybit.
+// It wires up the client and satisfies the interface so call sites can be
+// written against exchange.Exchange today; every endpoint currently
+// returns ErrNotImplemented.
+package bybit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eAndrius/cryptsy-go/pkg/exchange"
+	"github.com/eAndrius/cryptsy-go/pkg/exchange/rest"
+)
+
+// ErrNotImplemented is returned by endpoints not yet wired up for Bybit.
+var ErrNotImplemented = errors.New("bybit: not implemented")
+
+const (
+	API_PROTOCOL string = "https://"
+	API_HOST     string = "api.bybit.com"
+
+	defaultRecvWindow = 5 * time.Second
+)
+
+type Api struct {
+	client *rest.Client
+}
+
+var _ exchange.Exchange = (*Api)(nil)
+
+// New builds an Api using Bybit's HMAC-SHA256 header signing scheme and the
+// real clock. Use NewWithSigner to inject a different Signer or Clock, e.g.
+// a fixed Clock in tests.
+func New(apiKey, apiSecret string) (*Api, error) {
+	signer := rest.BybitSigner{ApiKey: apiKey, ApiSecret: apiSecret, RecvWindow: defaultRecvWindow}
+	return NewWithSigner(signer, rest.RealClock{})
+}
+
+func NewWithSigner(signer rest.Signer, clock rest.Clock) (*Api, error) {
+	return &Api{
+		client: rest.NewClient(API_PROTOCOL+API_HOST, signer, nil, clock),
+	}, nil
+}
+
+func (api *Api) GetMarkets() (map[exchange.CurrencyPair]exchange.Market, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) GetDepth(pair exchange.CurrencyPair) (*exchange.Depth, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) GetBalances() (*exchange.Account, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) CreateOrder(pair exchange.CurrencyPair, action exchange.ActionType, price, quantity float64) (*exchange.Order, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) CancelOrder(pair exchange.CurrencyPair, orderId string) error {
+	return ErrNotImplemented
+}
+
+func (api *Api) GetOrderHistory(pair exchange.CurrencyPair, opts ...exchange.OptionalParameter) ([]exchange.Order, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) GetKlines(pair exchange.CurrencyPair, period exchange.KlinePeriod, size int, opts ...exchange.OptionalParameter) ([]exchange.Kline, error) {
+	return nil, ErrNotImplemented
+}
+
+func (api *Api) GetTrades(pair exchange.CurrencyPair) ([]exchange.Trade, error) {
+	return nil, ErrNotImplemented
+}
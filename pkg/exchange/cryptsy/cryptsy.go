@@ -0,0 +1,472 @@
+// Package cryptsy implements the exchange.Exchange interface against
+// Cryptsy's HTTP API.
+package cryptsy
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/eAndrius/cryptsy-go/pkg/exchange"
+	"github.com/eAndrius/cryptsy-go/pkg/exchange/rest"
+)
+
+var (
+	API_PROTOCOL string = "https://"
+	API_HOST     string = "api.cryptsy.com"
+	API_PATH     string = "/api"
+	API_IP       string // To be populated automatically
+)
+
+// ErrNotImplemented is the error for endpoints Cryptsy's public API has no
+// equivalent for. No method of Api currently returns it: GetKlines, the one
+// such endpoint, synthesizes its result from markettrades instead.
+var ErrNotImplemented = errors.New("cryptsy: not implemented")
+
+// Cryptsy's getmarkets response carries no tick size or minimum notional
+// fields, so these fall back to sensible, conservative defaults.
+const (
+	defaultPriceTickSize  = 0.00000001
+	defaultAmountTickSize = 0.00000001
+	defaultMinNotional    = 0.0001
+	defaultMinOrder       = 0.001
+)
+
+// marketTradesDatetimeLayout matches the "datetime" field format markettrades
+// returns, e.g. "2015-01-01 13:02:20".
+const marketTradesDatetimeLayout = "2006-01-02 15:04:05"
+
+type Api struct {
+	client *rest.Client
+
+	marketIds map[exchange.CurrencyPair]int
+	markets   map[exchange.CurrencyPair]exchange.Market
+}
+
+var _ exchange.Exchange = (*Api)(nil)
+
+// New builds an Api using Cryptsy's own HMAC-SHA512 signing scheme and the
+// real clock. Use NewWithSigner to inject a different Signer or Clock, e.g.
+// a fixed Clock in tests.
+func New(publicKey, privateKey string) (*Api, error) {
+	return NewWithSigner(rest.CryptsySigner{PublicKey: publicKey, PrivateKey: privateKey}, rest.RealClock{})
+}
+
+func NewWithSigner(signer rest.Signer, clock rest.Clock) (api *Api, err error) {
+	// Resolve api host to IP to avoid time-consuming DNS queries
+	api_ip, err := net.LookupHost(API_HOST)
+	if err != nil {
+		return nil, err
+	}
+	API_IP = api_ip[0]
+	//API_HOST = API_IP
+
+	// Create HTTP pool
+	tr := &http.Transport{
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true}, //, MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS10},
+		MaxIdleConnsPerHost:   5,
+		DisableKeepAlives:     false,
+		DisableCompression:    false,
+		ResponseHeaderTimeout: 5 * time.Second,
+	}
+
+	api = &Api{
+		client:    rest.NewClient(API_PROTOCOL+API_HOST+API_PATH, signer, &http.Client{Transport: tr}, clock),
+		marketIds: make(map[exchange.CurrencyPair]int),
+		markets:   make(map[exchange.CurrencyPair]exchange.Market),
+	}
+
+	return api, nil
+}
+
+func (api *Api) query(params url.Values) (result string, err error) {
+	body, err := api.client.DoSigned("POST", "", params)
+	if err != nil {
+		return
+	}
+
+	return string(body), nil
+}
+
+// resolveMarketId looks up the numeric Cryptsy market id for pair, priming
+// the cache from GetMarkets if it hasn't been populated yet.
+func (api *Api) resolveMarketId(pair exchange.CurrencyPair) (int, error) {
+	if id, ok := api.marketIds[pair]; ok {
+		return id, nil
+	}
+
+	if _, err := api.GetMarkets(); err != nil {
+		return 0, err
+	}
+
+	id, ok := api.marketIds[pair]
+	if !ok {
+		return 0, fmt.Errorf("cryptsy: unknown market %s", pair)
+	}
+	return id, nil
+}
+
+func (api *Api) GetBalances() (*exchange.Account, error) {
+	resultJson, err := api.query(url.Values{"method": {"getinfo"}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, Error string
+		Return         map[string](map[string]string)
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	account := &exchange.Account{Balances: make(map[exchange.BalanceKey]float64, len(tmp.Return["balances_available"]))}
+	for key, value := range tmp.Return["balances_available"] {
+		balance, _ := strconv.ParseFloat(value, 64)
+		account.Balances[exchange.BalanceKey(key)] = balance
+	}
+
+	return account, nil
+}
+
+func (api *Api) GetMarkets() (map[exchange.CurrencyPair]exchange.Market, error) {
+	resultJson, err := api.query(url.Values{"method": {"getmarkets"}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, Error string
+		Return         [](map[string]string)
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	markets := make(map[exchange.CurrencyPair]exchange.Market)
+	for _, market := range tmp.Return {
+		pair := exchange.CurrencyPair{
+			Primary:   market["primary_currency_code"],
+			Secondary: market["secondary_currency_code"],
+		}
+
+		marketId, _ := strconv.Atoi(market["marketid"])
+		api.marketIds[pair] = marketId
+
+		minOrder, _ := strconv.ParseFloat(market["min_trade"], 64)
+		if minOrder == 0 {
+			minOrder = defaultMinOrder
+		}
+
+		value := exchange.Market{
+			Pair:           pair,
+			Label:          market["label"],
+			MakerFee:       0.002, // In normalized percentage
+			TakerFee:       0.003, // In normalized percentage
+			MinOrder:       minOrder,
+			PriceTickSize:  defaultPriceTickSize,
+			AmountTickSize: defaultAmountTickSize,
+			MinNotional:    defaultMinNotional,
+		}
+
+		markets[pair] = value
+		api.markets[pair] = value
+	}
+
+	return markets, nil
+}
+
+func (api *Api) GetDepth(pair exchange.CurrencyPair) (*exchange.Depth, error) {
+	marketId, err := api.resolveMarketId(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	resultJson, err := api.query(url.Values{"method": {"depth"}, "marketid": {strconv.Itoa(marketId)}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, Error string
+		Return         map[string]interface{}
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	depth := &exchange.Depth{Pair: pair}
+	for key, value := range tmp.Return {
+		for _, order := range value.([]interface{}) {
+			price, _ := strconv.ParseFloat(order.([]interface{})[0].(string), 64)
+			quantity, _ := strconv.ParseFloat(order.([]interface{})[1].(string), 64)
+
+			level := exchange.PriceLevel{Price: price, Quantity: quantity}
+			if key == "buy" {
+				depth.Bids = append(depth.Bids, level)
+			} else if key == "sell" {
+				depth.Asks = append(depth.Asks, level)
+			}
+		}
+	}
+
+	return depth, nil
+}
+
+func (api *Api) GetTrades(pair exchange.CurrencyPair) ([]exchange.Trade, error) {
+	marketId, err := api.resolveMarketId(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	resultJson, err := api.query(url.Values{"method": {"markettrades"}, "marketid": {strconv.Itoa(marketId)}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, Error string
+		Return         []map[string]interface{}
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	trades := make([]exchange.Trade, 0, len(tmp.Return))
+	for _, t := range tmp.Return {
+		price, _ := strconv.ParseFloat(fmt.Sprint(t["tradeprice"]), 64)
+		quantity, _ := strconv.ParseFloat(fmt.Sprint(t["quantity"]), 64)
+		tradeTime, _ := time.Parse(marketTradesDatetimeLayout, fmt.Sprint(t["datetime"]))
+
+		trades = append(trades, exchange.Trade{
+			Time:     tradeTime,
+			Price:    price,
+			Quantity: quantity,
+		})
+	}
+
+	return trades, nil
+}
+
+func (api *Api) CreateOrder(pair exchange.CurrencyPair, action exchange.ActionType, price, quantity float64) (*exchange.Order, error) {
+	marketId, err := api.resolveMarketId(pair)
+	if err != nil {
+		return nil, err
+	}
+	market := api.markets[pair]
+
+	price = exchange.RoundDown(price, market.PriceTickSize)
+	quantity = exchange.RoundDown(quantity, market.AmountTickSize)
+	if price <= 0 || quantity <= 0 {
+		return nil, exchange.ErrTickSize
+	}
+	if market.MinOrder > 0 && quantity < market.MinOrder {
+		return nil, exchange.ErrMinOrder
+	}
+	if market.MinNotional > 0 && price*quantity < market.MinNotional {
+		return nil, exchange.ErrMinNotional
+	}
+
+	resultJson, err := api.query(url.Values{"method": {"createorder"}, "marketid": {strconv.Itoa(marketId)}, "ordertype": {string(action)},
+		"quantity": {strconv.FormatFloat(quantity, 'f', 8, 64)}, "price": {strconv.FormatFloat(price, 'f', 8, 64)}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, OrderId string
+		Error            string
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	return &exchange.Order{
+		Id:       tmp.OrderId,
+		Pair:     pair,
+		Action:   action,
+		Price:    price,
+		Quantity: quantity,
+	}, nil
+}
+
+func (api *Api) CancelOrder(pair exchange.CurrencyPair, orderId string) error {
+	resultJson, err := api.query(url.Values{"method": {"cancelorder"}, "orderid": {orderId}})
+	if err != nil {
+		return err
+	}
+
+	type resp struct {
+		Success, Return, Error string
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return errors.New(tmp.Error)
+	}
+
+	return nil
+}
+
+// CancelAllOrders cancels every open order on the account. It has no
+// equivalent in exchange.Exchange since most venues require cancelling
+// markets individually.
+func (api *Api) CancelAllOrders() error {
+	resultJson, err := api.query(url.Values{"method": {"cancelallorders"}})
+	if err != nil {
+		return err
+	}
+
+	type resp struct {
+		Success, Error string
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return errors.New(tmp.Error)
+	}
+
+	return nil
+}
+
+func (api *Api) GetOrderHistory(pair exchange.CurrencyPair, opts ...exchange.OptionalParameter) ([]exchange.Order, error) {
+	resultJson, err := api.query(url.Values{"method": {"allmyorders"}})
+	if err != nil {
+		return nil, err
+	}
+
+	type resp struct {
+		Success, Error string
+		Return         []map[string]interface{}
+	}
+	var tmp resp
+
+	json.Unmarshal([]byte(resultJson), &tmp)
+
+	if tmp.Success != "1" {
+		return nil, errors.New(tmp.Error)
+	}
+
+	marketId, err := api.resolveMarketId(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]exchange.Order, 0, len(tmp.Return))
+	for _, value := range tmp.Return {
+		if id, _ := strconv.Atoi(fmt.Sprint(value["marketid"])); id != marketId {
+			continue
+		}
+
+		orders = append(orders, exchange.Order{
+			Id:   fmt.Sprint(value["orderid"]),
+			Pair: pair,
+		})
+	}
+
+	return orders, nil
+}
+
+// GetKlines synthesizes OHLCV candles from markettrades, since Cryptsy's
+// public API has no native kline endpoint.
+func (api *Api) GetKlines(pair exchange.CurrencyPair, period exchange.KlinePeriod, size int, opts ...exchange.OptionalParameter) ([]exchange.Kline, error) {
+	bucket := period.Duration()
+	if bucket <= 0 {
+		return nil, fmt.Errorf("cryptsy: unsupported kline period %q", period)
+	}
+
+	trades, err := api.GetTrades(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	var since, until time.Time
+	for _, opt := range opts {
+		if t, ok := opt[exchange.ParamSince].(time.Time); ok {
+			since = t
+		}
+		if t, ok := opt[exchange.ParamUntil].(time.Time); ok {
+			until = t
+		}
+	}
+
+	// markettrades returns newest-first; sort ascending so each bucket's
+	// Open/Close are taken from its chronologically first/last trade.
+	sort.Slice(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+
+	byOpenTime := make(map[int64]*exchange.Kline)
+	var openTimes []int64
+
+	for _, trade := range trades {
+		if !since.IsZero() && trade.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !trade.Time.Before(until) {
+			continue
+		}
+
+		openTime := trade.Time.Truncate(bucket)
+		key := openTime.Unix()
+
+		kline, ok := byOpenTime[key]
+		if !ok {
+			kline = &exchange.Kline{OpenTime: openTime, Open: trade.Price, High: trade.Price, Low: trade.Price}
+			byOpenTime[key] = kline
+			openTimes = append(openTimes, key)
+		}
+
+		if trade.Price > kline.High {
+			kline.High = trade.Price
+		}
+		if trade.Price < kline.Low {
+			kline.Low = trade.Price
+		}
+		kline.Close = trade.Price
+		kline.Volume += trade.Quantity
+		kline.Trades++
+	}
+
+	sort.Slice(openTimes, func(i, j int) bool { return openTimes[i] < openTimes[j] })
+
+	klines := make([]exchange.Kline, 0, len(openTimes))
+	for _, key := range openTimes {
+		klines = append(klines, *byOpenTime[key])
+	}
+
+	if size > 0 && len(klines) > size {
+		klines = klines[len(klines)-size:]
+	}
+
+	return klines, nil
+}
@@ -0,0 +1,180 @@
+// Package exchange defines a venue-agnostic trading interface and the value
+// types shared by its backends (pkg/exchange/cryptsy, pkg/exchange/bybit, ...).
+package exchange
+
+import "time"
+
+// ActionType identifies the side of an order.
+type ActionType string
+
+const (
+	ActionBuy  ActionType = "buy"
+	ActionSell ActionType = "sell"
+)
+
+// CurrencyPair identifies a tradable market by its primary (base) and
+// secondary (quote) currency codes, e.g. {"BTC", "USD"}.
+type CurrencyPair struct {
+	Primary   string
+	Secondary string
+}
+
+func (p CurrencyPair) String() string {
+	return p.Primary + "_" + p.Secondary
+}
+
+// PriceLevel is a single price/quantity entry in an order book or trade tape.
+type PriceLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// Depth is a snapshot of a market's order book.
+type Depth struct {
+	Pair CurrencyPair
+	Bids []PriceLevel // highest price first
+	Asks []PriceLevel // lowest price first
+}
+
+// Ticker is a market summary.
+type Ticker struct {
+	Pair   CurrencyPair
+	Last   float64
+	Bid    float64
+	Ask    float64
+	High   float64
+	Low    float64
+	Volume float64
+}
+
+// Trade is a single executed trade on a market.
+type Trade struct {
+	Time     time.Time
+	Price    float64
+	Quantity float64
+}
+
+// Order is a placed or historical account order.
+type Order struct {
+	Id       string
+	Pair     CurrencyPair
+	Action   ActionType
+	Price    float64
+	Quantity float64
+	Filled   float64
+	Status   string
+	Time     time.Time
+}
+
+// BalanceKey identifies a currency code in an Account's balances, e.g. "BTC".
+type BalanceKey string
+
+// Account holds a venue's balances keyed by currency code.
+type Account struct {
+	Balances map[BalanceKey]float64
+}
+
+// Market describes a tradable pair's metadata as reported by a venue.
+type Market struct {
+	Pair     CurrencyPair
+	Label    string
+	Volume   float64
+	MakerFee float64 // In normalized percentage
+	TakerFee float64 // In normalized percentage
+	MinOrder float64
+
+	// PriceTickSize and AmountTickSize are the smallest increments price
+	// and quantity may be expressed in; CreateOrder rounds down to them.
+	// MinNotional is the minimum allowed price*quantity for an order.
+	PriceTickSize  float64
+	AmountTickSize float64
+	MinNotional    float64
+}
+
+// KlinePeriod is a candle bucket width.
+type KlinePeriod string
+
+const (
+	KLINE_1M  KlinePeriod = "1m"
+	KLINE_5M  KlinePeriod = "5m"
+	KLINE_15M KlinePeriod = "15m"
+	KLINE_1H  KlinePeriod = "1h"
+	KLINE_4H  KlinePeriod = "4h"
+	KLINE_1D  KlinePeriod = "1d"
+)
+
+// Duration returns the bucket width p represents, or 0 if p is unrecognized.
+func (p KlinePeriod) Duration() time.Duration {
+	switch p {
+	case KLINE_1M:
+		return time.Minute
+	case KLINE_5M:
+		return 5 * time.Minute
+	case KLINE_15M:
+		return 15 * time.Minute
+	case KLINE_1H:
+		return time.Hour
+	case KLINE_4H:
+		return 4 * time.Hour
+	case KLINE_1D:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Kline is a single OHLCV candle.
+type Kline struct {
+	OpenTime time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+	Trades   int
+}
+
+// OptionalParameter carries optional, venue-agnostic call parameters such as
+// pagination or time-window filters. Backends interpret the keys they
+// understand and ignore the rest.
+type OptionalParameter map[string]interface{}
+
+// Well-known OptionalParameter keys for GetOrderHistory and GetKlines. Each
+// backend honours only the keys it understands; for example, the Cryptsy
+// backend's GetKlines reads ParamSince and ParamUntil, and its
+// GetOrderHistory reads none of them.
+const (
+	ParamSince = "since" // time.Time: lower bound, inclusive
+	ParamUntil = "until" // time.Time: upper bound, exclusive
+	ParamLimit = "limit" // int: maximum number of results
+)
+
+// OptionSince builds an OptionalParameter restricting results to those at or
+// after t.
+func OptionSince(t time.Time) OptionalParameter {
+	return OptionalParameter{ParamSince: t}
+}
+
+// OptionUntil builds an OptionalParameter restricting results to those
+// strictly before t.
+func OptionUntil(t time.Time) OptionalParameter {
+	return OptionalParameter{ParamUntil: t}
+}
+
+// OptionLimit builds an OptionalParameter capping the number of results.
+func OptionLimit(n int) OptionalParameter {
+	return OptionalParameter{ParamLimit: n}
+}
+
+// Exchange is the common surface implemented by every supported venue
+// backend, so callers can swap venues without rewriting call sites.
+type Exchange interface {
+	GetMarkets() (map[CurrencyPair]Market, error)
+	GetDepth(pair CurrencyPair) (*Depth, error)
+	GetBalances() (*Account, error)
+	CreateOrder(pair CurrencyPair, action ActionType, price, quantity float64) (*Order, error)
+	CancelOrder(pair CurrencyPair, orderId string) error
+	GetOrderHistory(pair CurrencyPair, opts ...OptionalParameter) ([]Order, error)
+	GetKlines(pair CurrencyPair, period KlinePeriod, size int, opts ...OptionalParameter) ([]Kline, error)
+	GetTrades(pair CurrencyPair) ([]Trade, error)
+}
@@ -0,0 +1,41 @@
+package exchange
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrTickSize is returned when a price or quantity is smaller than the
+// market's tick size and would round down to zero.
+var ErrTickSize = errors.New("exchange: price or quantity violates tick size")
+
+// ErrMinNotional is returned when price*quantity falls below the market's
+// minimum order value.
+var ErrMinNotional = errors.New("exchange: order value below minimum notional")
+
+// ErrMinOrder is returned when an order's quantity falls below the market's
+// minimum order quantity.
+var ErrMinOrder = errors.New("exchange: order quantity below minimum order size")
+
+// RoundDown floors value to the nearest multiple of tick. A non-positive
+// tick is treated as "no constraint" and value is returned unchanged.
+func RoundDown(value, tick float64) float64 {
+	if tick <= 0 {
+		return value
+	}
+	steps := value / tick
+	// Snap values that are a multiple of tick up to floating-point error
+	// (e.g. 0.58 / 0.00000001) so flooring doesn't drop a whole tick.
+	if nearest := math.Round(steps); math.Abs(steps-nearest) < 1e-9*math.Max(1, math.Abs(steps)) {
+		steps = nearest
+	}
+	return math.Floor(steps) * tick
+}
+
+// CalculateFees returns the maker and taker fee owed on an order of size
+// quantity at price against market, from market's MakerFee and TakerFee
+// rates. action does not affect the result.
+func CalculateFees(market Market, action ActionType, price, quantity float64) (makerFee, takerFee float64) {
+	notional := price * quantity
+	return notional * market.MakerFee, notional * market.TakerFee
+}
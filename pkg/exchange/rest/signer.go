@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CryptsySigner implements Cryptsy's scheme: an HMAC-SHA512 digest of the
+// form-encoded body, sent in the Sign header alongside the public key.
+type CryptsySigner struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+func (s CryptsySigner) Sign(method, path string, params url.Values, clock Clock) (http.Header, []byte) {
+	params.Set("nonce", strconv.FormatInt(clock.Now().UnixNano(), 10))
+	body := []byte(params.Encode())
+
+	mac := hmac.New(sha512.New, []byte(s.PrivateKey))
+	mac.Write(body)
+	sign := hex.EncodeToString(mac.Sum(nil))
+
+	header := http.Header{}
+	header.Set("Key", s.PublicKey)
+	header.Set("Sign", sign)
+	header.Set("Connection", "Keep-Alive")
+	header.Set("Cache-Control", "no-cache, must-revalidate")
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return header, body
+}
+
+// BybitSigner implements Bybit's v5 scheme: an HMAC-SHA256 digest of
+// timestamp+apiKey+recvWindow+queryString, hex-encoded into the
+// X-BAPI-SIGN header alongside X-BAPI-TIMESTAMP and X-BAPI-RECV-WINDOW.
+type BybitSigner struct {
+	ApiKey     string
+	ApiSecret  string
+	RecvWindow time.Duration
+}
+
+func (s BybitSigner) Sign(method, path string, params url.Values, clock Clock) (http.Header, []byte) {
+	timestamp := strconv.FormatInt(clock.Now().UnixNano()/int64(time.Millisecond), 10)
+	recvWindow := strconv.FormatInt(int64(s.RecvWindow/time.Millisecond), 10)
+	query := params.Encode()
+
+	mac := hmac.New(sha256.New, []byte(s.ApiSecret))
+	mac.Write([]byte(timestamp + s.ApiKey + recvWindow + query))
+	sign := hex.EncodeToString(mac.Sum(nil))
+
+	header := http.Header{}
+	header.Set("X-BAPI-API-KEY", s.ApiKey)
+	header.Set("X-BAPI-SIGN", sign)
+	header.Set("X-BAPI-TIMESTAMP", timestamp)
+	header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return header, []byte(query)
+}
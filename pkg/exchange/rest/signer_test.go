@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+func TestCryptsySignerSign(t *testing.T) {
+	clock := fixedClock{t: time.Date(2015, 1, 1, 13, 2, 20, 0, time.UTC)}
+	signer := CryptsySigner{PublicKey: "pub-key", PrivateKey: "priv-key"}
+
+	params := url.Values{"method": {"getinfo"}}
+	header, body := signer.Sign("POST", "/api", params, clock)
+
+	wantBody := "method=getinfo&nonce=" + strconv.FormatInt(clock.t.UnixNano(), 10)
+	if string(body) != wantBody {
+		t.Fatalf("body = %q, want %q", body, wantBody)
+	}
+
+	mac := hmac.New(sha512.New, []byte(signer.PrivateKey))
+	mac.Write([]byte(wantBody))
+	wantSign := hex.EncodeToString(mac.Sum(nil))
+
+	if got := header.Get("Key"); got != signer.PublicKey {
+		t.Errorf("Key header = %q, want %q", got, signer.PublicKey)
+	}
+	if got := header.Get("Sign"); got != wantSign {
+		t.Errorf("Sign header = %q, want %q", got, wantSign)
+	}
+	if got := header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type header = %q", got)
+	}
+	if got := header.Get("Content-Length"); got != strconv.Itoa(len(body)) {
+		t.Errorf("Content-Length header = %q, want %q", got, strconv.Itoa(len(body)))
+	}
+}
+
+func TestBybitSignerSign(t *testing.T) {
+	clock := fixedClock{t: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	signer := BybitSigner{ApiKey: "api-key", ApiSecret: "api-secret", RecvWindow: 5 * time.Second}
+
+	params := url.Values{"symbol": {"BTCUSDT"}}
+	header, body := signer.Sign("GET", "/v5/order/create", params, clock)
+
+	wantQuery := params.Encode()
+	if string(body) != wantQuery {
+		t.Fatalf("body = %q, want %q", body, wantQuery)
+	}
+
+	wantTimestamp := strconv.FormatInt(clock.t.UnixNano()/int64(time.Millisecond), 10)
+	wantRecvWindow := strconv.FormatInt(int64(signer.RecvWindow/time.Millisecond), 10)
+
+	mac := hmac.New(sha256.New, []byte(signer.ApiSecret))
+	mac.Write([]byte(wantTimestamp + signer.ApiKey + wantRecvWindow + wantQuery))
+	wantSign := hex.EncodeToString(mac.Sum(nil))
+
+	if got := header.Get("X-BAPI-API-KEY"); got != signer.ApiKey {
+		t.Errorf("X-BAPI-API-KEY header = %q, want %q", got, signer.ApiKey)
+	}
+	if got := header.Get("X-BAPI-TIMESTAMP"); got != wantTimestamp {
+		t.Errorf("X-BAPI-TIMESTAMP header = %q, want %q", got, wantTimestamp)
+	}
+	if got := header.Get("X-BAPI-RECV-WINDOW"); got != wantRecvWindow {
+		t.Errorf("X-BAPI-RECV-WINDOW header = %q, want %q", got, wantRecvWindow)
+	}
+	if got := header.Get("X-BAPI-SIGN"); got != wantSign {
+		t.Errorf("X-BAPI-SIGN header = %q, want %q", got, wantSign)
+	}
+}
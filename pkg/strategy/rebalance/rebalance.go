@@ -0,0 +1,225 @@
+// Package rebalance implements a target-weight portfolio rebalancing
+// strategy on top of exchange.Exchange: it prices the account's holdings,
+// computes the delta against a target allocation, and places limit orders
+// to close the gap.
+package rebalance
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/eAndrius/cryptsy-go/pkg/exchange"
+)
+
+// OrderAction is a single order the strategy wants placed to move the
+// portfolio toward its target allocation.
+type OrderAction struct {
+	Pair     exchange.CurrencyPair
+	Action   exchange.ActionType
+	Price    float64
+	Quantity float64
+}
+
+// Config controls a Rebalancer.
+type Config struct {
+	// Target maps each asset to its target fraction of total portfolio
+	// value; the values must sum to 1.0.
+	Target map[exchange.BalanceKey]float64
+	// Quote is the currency target weights and order prices are expressed
+	// against, e.g. "USD".
+	Quote exchange.BalanceKey
+	// Tolerance is the fraction an asset's actual weight may drift from
+	// its target before the strategy rebalances it.
+	Tolerance float64
+	// Interval is how often Run re-evaluates the portfolio.
+	Interval time.Duration
+	// OnStart rebalances once immediately when Run is called, rather than
+	// waiting for the first Interval tick.
+	OnStart bool
+	// DryRun logs the OrderActions a tick would take instead of placing them.
+	DryRun bool
+	// MaxSlippage skips an asset's market when its top-of-book spread,
+	// as a fraction of the mid price, exceeds this threshold.
+	MaxSlippage float64
+}
+
+// Rebalancer runs Config against an Exchange, tracking in-flight orders so
+// unfilled ones are cancelled and replaced on the next tick.
+type Rebalancer struct {
+	Config
+	exchange exchange.Exchange
+	active   map[exchange.CurrencyPair]*exchange.Order
+}
+
+// New validates cfg and returns a Rebalancer for ex.
+func New(ex exchange.Exchange, cfg Config) (*Rebalancer, error) {
+	var total float64
+	for _, weight := range cfg.Target {
+		total += weight
+	}
+	if math.Abs(total-1.0) > 1e-6 {
+		return nil, fmt.Errorf("rebalance: target weights sum to %f, want 1.0", total)
+	}
+	if cfg.Quote == "" {
+		return nil, errors.New("rebalance: Quote is required")
+	}
+
+	return &Rebalancer{
+		Config:   cfg,
+		exchange: ex,
+		active:   make(map[exchange.CurrencyPair]*exchange.Order),
+	}, nil
+}
+
+// Run evaluates the portfolio every Interval until stop is closed.
+func (r *Rebalancer) Run(stop <-chan struct{}) error {
+	if r.OnStart {
+		if err := r.Tick(); err != nil {
+			log.Printf("rebalance: initial tick failed: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			if err := r.Tick(); err != nil {
+				log.Printf("rebalance: tick failed: %v", err)
+			}
+		}
+	}
+}
+
+// Tick prices the portfolio once, computes the actions needed to bring it
+// within tolerance of Target, and places them (or logs them, in DryRun).
+func (r *Rebalancer) Tick() error {
+	account, err := r.exchange.GetBalances()
+	if err != nil {
+		return err
+	}
+
+	values, total, err := r.valueHoldings(account)
+	if err != nil {
+		return err
+	}
+
+	for asset, weight := range r.Target {
+		if asset == r.Quote {
+			continue
+		}
+
+		pair := exchange.CurrencyPair{Primary: string(asset), Secondary: string(r.Quote)}
+
+		depth, err := r.exchange.GetDepth(pair)
+		if err != nil {
+			log.Printf("rebalance: %s: %v", pair, err)
+			continue
+		}
+		bid, ask, ok := topOfBook(depth)
+		if !ok {
+			continue
+		}
+		mid := (bid + ask) / 2
+
+		if mid > 0 && (ask-bid)/mid > r.MaxSlippage {
+			log.Printf("rebalance: %s: spread %.4f%% exceeds MaxSlippage, skipping", pair, 100*(ask-bid)/mid)
+			continue
+		}
+
+		currentValue := values[asset]
+		targetValue := total * weight
+		drift := targetValue - currentValue
+		if total > 0 && math.Abs(drift)/total <= r.Tolerance {
+			continue
+		}
+
+		action := OrderAction{Pair: pair}
+		if drift > 0 {
+			action.Action = exchange.ActionBuy
+			action.Price = ask
+			action.Quantity = drift / ask
+		} else {
+			action.Action = exchange.ActionSell
+			action.Price = bid
+			action.Quantity = -drift / bid
+		}
+
+		if err := r.place(action); err != nil {
+			log.Printf("rebalance: %s: %v", pair, err)
+		}
+	}
+
+	return nil
+}
+
+// valueHoldings prices every non-zero, non-quote balance that Target tracks
+// at its mid price against Quote, returning each asset's value plus the
+// portfolio total (including the quote balance itself). Balances not in
+// Target, and assets whose book has no bid or no ask, are left out of both.
+func (r *Rebalancer) valueHoldings(account *exchange.Account) (values map[exchange.BalanceKey]float64, total float64, err error) {
+	values = make(map[exchange.BalanceKey]float64, len(account.Balances))
+
+	for asset, amount := range account.Balances {
+		if asset == r.Quote {
+			values[asset] = amount
+			total += amount
+			continue
+		}
+		if _, tracked := r.Target[asset]; !tracked || amount == 0 {
+			continue
+		}
+
+		depth, err := r.exchange.GetDepth(exchange.CurrencyPair{Primary: string(asset), Secondary: string(r.Quote)})
+		if err != nil {
+			return nil, 0, err
+		}
+		bid, ask, ok := topOfBook(depth)
+		if !ok {
+			continue
+		}
+
+		value := amount * (bid + ask) / 2
+		values[asset] = value
+		total += value
+	}
+
+	return values, total, nil
+}
+
+// place cancels any unfilled order still active for action.Pair and submits
+// the replacement, unless DryRun is set.
+func (r *Rebalancer) place(action OrderAction) error {
+	if r.DryRun {
+		log.Printf("rebalance: [dry run] %s %s %f @ %f", action.Action, action.Pair, action.Quantity, action.Price)
+		return nil
+	}
+
+	if existing, ok := r.active[action.Pair]; ok {
+		if err := r.exchange.CancelOrder(action.Pair, existing.Id); err != nil {
+			return err
+		}
+		delete(r.active, action.Pair)
+	}
+
+	order, err := r.exchange.CreateOrder(action.Pair, action.Action, action.Price, action.Quantity)
+	if err != nil {
+		return err
+	}
+
+	r.active[action.Pair] = order
+	return nil
+}
+
+func topOfBook(depth *exchange.Depth) (bid, ask float64, ok bool) {
+	if len(depth.Bids) == 0 || len(depth.Asks) == 0 {
+		return 0, 0, false
+	}
+	return depth.Bids[0].Price, depth.Asks[0].Price, true
+}
@@ -0,0 +1,143 @@
+package stream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/eAndrius/cryptsy-go/pkg/exchange"
+)
+
+// unsynced marks a book that has a REST snapshot but hasn't yet observed a
+// live delta to anchor its sequence to. REST GetDepth carries no sequence
+// number of its own, so the book can't know what to expect next until the
+// first DepthEvent arrives.
+const unsynced int64 = -1
+
+// OrderBook is a local order book snapshot kept in sync with a venue's
+// diff-depth WebSocket feed. It's seeded from a REST GetDepth call and then
+// updated from DepthEvents; a sequence gap means an update was missed and
+// the book must be reseeded from REST before it can be trusted again.
+type OrderBook struct {
+	Pair exchange.CurrencyPair
+
+	mu            sync.Mutex
+	bids          map[float64]float64
+	asks          map[float64]float64
+	sequence      int64
+	resnapshoting bool
+}
+
+// NewOrderBook returns an empty, unseeded book for pair.
+func NewOrderBook(pair exchange.CurrencyPair) *OrderBook {
+	return &OrderBook{
+		Pair:     pair,
+		bids:     make(map[float64]float64),
+		asks:     make(map[float64]float64),
+		sequence: unsynced,
+	}
+}
+
+// Seed replaces the book's contents with a REST snapshot. Since the
+// snapshot carries no sequence number, the book stays unsynced until the
+// next DepthEvent arrives, at which point Apply adopts that event's
+// sequence as the new baseline.
+func (b *OrderBook) Seed(depth *exchange.Depth) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = make(map[float64]float64, len(depth.Bids))
+	for _, level := range depth.Bids {
+		b.bids[level.Price] = level.Quantity
+	}
+
+	b.asks = make(map[float64]float64, len(depth.Asks))
+	for _, level := range depth.Asks {
+		b.asks[level.Price] = level.Quantity
+	}
+
+	b.sequence = unsynced
+}
+
+// Apply merges a DepthEvent into the book. If the book is unsynced (fresh
+// off a Seed), ev is treated as the resync point regardless of its sequence
+// number. Otherwise Apply returns gap=true, without applying the update,
+// when ev.Sequence isn't the book's next expected sequence number — the
+// caller should reseed the book (e.g. via Seed) and discard events until a
+// fresh one arrives.
+func (b *OrderBook) Apply(ev DepthEvent) (gap bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.sequence == unsynced {
+		b.sequence = ev.Sequence - 1
+	}
+
+	if ev.Sequence != b.sequence+1 {
+		return true
+	}
+
+	for _, level := range ev.Bids {
+		applyLevel(b.bids, level)
+	}
+	for _, level := range ev.Asks {
+		applyLevel(b.asks, level)
+	}
+	b.sequence = ev.Sequence
+
+	return false
+}
+
+// BeginResnapshot reports whether a resnapshot should be started for a gap:
+// it returns true (and marks one in flight) only if none is already
+// running, so a burst of gapped events triggers at most one REST call.
+// Callers must call EndResnapshot once that call completes.
+func (b *OrderBook) BeginResnapshot() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.resnapshoting {
+		return false
+	}
+	b.resnapshoting = true
+	return true
+}
+
+// EndResnapshot clears the in-flight marker set by BeginResnapshot.
+func (b *OrderBook) EndResnapshot() {
+	b.mu.Lock()
+	b.resnapshoting = false
+	b.mu.Unlock()
+}
+
+func applyLevel(side map[float64]float64, level exchange.PriceLevel) {
+	if level.Quantity == 0 {
+		delete(side, level.Price)
+		return
+	}
+	side[level.Price] = level.Quantity
+}
+
+// Snapshot returns the book's current state as a Depth, with bids sorted
+// highest-first and asks sorted lowest-first.
+func (b *OrderBook) Snapshot() *exchange.Depth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	depth := &exchange.Depth{
+		Pair: b.Pair,
+		Bids: make([]exchange.PriceLevel, 0, len(b.bids)),
+		Asks: make([]exchange.PriceLevel, 0, len(b.asks)),
+	}
+
+	for price, quantity := range b.bids {
+		depth.Bids = append(depth.Bids, exchange.PriceLevel{Price: price, Quantity: quantity})
+	}
+	for price, quantity := range b.asks {
+		depth.Asks = append(depth.Asks, exchange.PriceLevel{Price: price, Quantity: quantity})
+	}
+
+	sort.Slice(depth.Bids, func(i, j int) bool { return depth.Bids[i].Price > depth.Bids[j].Price })
+	sort.Slice(depth.Asks, func(i, j int) bool { return depth.Asks[i].Price < depth.Asks[j].Price })
+
+	return depth
+}
@@ -0,0 +1,32 @@
+package stream
+
+import "github.com/eAndrius/cryptsy-go/pkg/exchange"
+
+// TradeEvent is a single trade pushed over a public trade-stream subscription.
+type TradeEvent struct {
+	Pair  exchange.CurrencyPair
+	Trade exchange.Trade
+}
+
+// DepthEvent is an order book delta: a set of price levels to upsert (or
+// remove, when Quantity is 0) and the venue's sequence number for the
+// update, used to detect gaps against the locally maintained book.
+type DepthEvent struct {
+	Pair     exchange.CurrencyPair
+	Sequence int64
+	Bids     []exchange.PriceLevel
+	Asks     []exchange.PriceLevel
+}
+
+// BalanceEvent is pushed on the private user-data stream whenever an
+// account balance changes.
+type BalanceEvent struct {
+	Currency string
+	Balance  float64
+}
+
+// OrderUpdateEvent is pushed on the private user-data stream for fills and
+// other order state transitions.
+type OrderUpdateEvent struct {
+	Order exchange.Order
+}
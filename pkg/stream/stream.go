@@ -0,0 +1,320 @@
+// Package stream maintains a persistent WebSocket connection to a venue's
+// real-time feed, so callers no longer have to poll REST endpoints for
+// order book, trade, and account updates.
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/eAndrius/cryptsy-go/pkg/exchange"
+)
+
+const (
+	reconnectBackoff = 2 * time.Second
+	pingInterval     = 30 * time.Second
+	pongTimeout      = 45 * time.Second
+)
+
+// Stream is a reconnecting WebSocket client exposing typed event channels.
+// Dispatched events are non-blocking best-effort: a channel is only written
+// to if it has room, so a slow consumer can't stall the read loop.
+type Stream struct {
+	URL  string
+	rest exchange.Exchange
+
+	Trades       chan TradeEvent
+	Depths       chan DepthEvent
+	Balances     chan BalanceEvent
+	OrderUpdates chan OrderUpdateEvent
+
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	books  map[exchange.CurrencyPair]*OrderBook
+	subs   []json.RawMessage // replayed against the connection on every (re)connect
+	closed chan struct{}
+}
+
+// New returns a Stream that dials wsURL and, where a subscription needs an
+// initial REST snapshot (depth), seeds it via rest.
+func New(wsURL string, rest exchange.Exchange) *Stream {
+	return &Stream{
+		URL:          wsURL,
+		rest:         rest,
+		Trades:       make(chan TradeEvent, 256),
+		Depths:       make(chan DepthEvent, 256),
+		Balances:     make(chan BalanceEvent, 64),
+		OrderUpdates: make(chan OrderUpdateEvent, 64),
+		books:        make(map[exchange.CurrencyPair]*OrderBook),
+		closed:       make(chan struct{}),
+	}
+}
+
+// Connect dials the stream and starts the reconnecting read loop in the
+// background. It returns once the first connection attempt succeeds.
+func (s *Stream) Connect() error {
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+
+	go s.keepalive(conn)
+	go s.run(conn)
+
+	return nil
+}
+
+// Close stops the reconnect loop and closes the underlying connection.
+func (s *Stream) Close() error {
+	close(s.closed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+func (s *Stream) dial() (*websocket.Conn, error) {
+	if _, err := url.Parse(s.URL); err != nil {
+		return nil, err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		return nil
+	})
+
+	s.mu.Lock()
+	subs := append([]json.RawMessage(nil), s.subs...)
+	s.mu.Unlock()
+	for _, sub := range subs {
+		if err := conn.WriteMessage(websocket.TextMessage, sub); err != nil {
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// run reads frames off conn until it errors or Close is called, then
+// reconnects and resubscribes, repeating until Close is called.
+func (s *Stream) run(conn *websocket.Conn) {
+	for {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				break
+			}
+			s.dispatch(decompress(data))
+		}
+
+		select {
+		case <-s.closed:
+			return
+		default:
+		}
+
+		time.Sleep(reconnectBackoff)
+
+		next, err := s.dial()
+		if err != nil {
+			log.Printf("stream: reconnect failed: %v", err)
+			continue
+		}
+
+		s.mu.Lock()
+		s.conn = next
+		s.mu.Unlock()
+
+		go s.keepalive(next)
+		conn = next
+	}
+}
+
+func (s *Stream) keepalive(conn *websocket.Conn) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			current := s.conn
+			s.mu.Unlock()
+			if current != conn {
+				return // superseded by a reconnect
+			}
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// decompress transparently gzip-decompresses data if it looks gzipped;
+// many venues compress their WS frames, plain JSON otherwise.
+func decompress(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return data
+	}
+	defer r.Close()
+
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// subscribe records frame so it's replayed on every future (re)connect, and
+// sends it over the current connection if one is open.
+func (s *Stream) subscribe(frame json.RawMessage) error {
+	s.mu.Lock()
+	s.subs = append(s.subs, frame)
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.WriteMessage(websocket.TextMessage, frame)
+}
+
+// SubscribeDepth subscribes to order book updates for pair and maintains a
+// local OrderBook, seeded from a REST GetDepth call and kept in sync from
+// DepthEvents; a detected sequence gap triggers a resnapshot.
+func (s *Stream) SubscribeDepth(pair exchange.CurrencyPair) (*OrderBook, error) {
+	book := NewOrderBook(pair)
+
+	depth, err := s.rest.GetDepth(pair)
+	if err != nil {
+		return nil, err
+	}
+	book.Seed(depth)
+
+	s.mu.Lock()
+	s.books[pair] = book
+	s.mu.Unlock()
+
+	frame, err := json.Marshal(map[string]interface{}{"op": "subscribe", "channel": "depth", "pair": pair.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	return book, s.subscribe(frame)
+}
+
+// SubscribeTrades subscribes to the public trade feed for pair.
+func (s *Stream) SubscribeTrades(pair exchange.CurrencyPair) error {
+	frame, err := json.Marshal(map[string]interface{}{"op": "subscribe", "channel": "trades", "pair": pair.String()})
+	if err != nil {
+		return err
+	}
+	return s.subscribe(frame)
+}
+
+// SubscribeUserData subscribes to the private fills/order-update/balance
+// stream. auth is venue-specific, pre-signed credentials (e.g. a signed
+// login frame); Stream doesn't interpret it.
+func (s *Stream) SubscribeUserData(auth json.RawMessage) error {
+	return s.subscribe(auth)
+}
+
+// resnapshot reseeds book from REST after a sequence gap is detected. The
+// caller must have already claimed book.BeginResnapshot.
+func (s *Stream) resnapshot(book *OrderBook) {
+	defer book.EndResnapshot()
+
+	depth, err := s.rest.GetDepth(book.Pair)
+	if err != nil {
+		log.Printf("stream: resnapshot %s failed: %v", book.Pair, err)
+		return
+	}
+	book.Seed(depth)
+}
+
+func (s *Stream) dispatch(data []byte) {
+	var envelope struct {
+		Channel string          `json:"channel"`
+		Pair    string          `json:"pair"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return
+	}
+
+	switch envelope.Channel {
+	case "depth":
+		var ev DepthEvent
+		if err := json.Unmarshal(envelope.Payload, &ev); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		book := s.books[ev.Pair]
+		s.mu.Unlock()
+
+		if book != nil && book.Apply(ev) && book.BeginResnapshot() {
+			go s.resnapshot(book)
+		}
+
+		select {
+		case s.Depths <- ev:
+		default:
+		}
+	case "trades":
+		var ev TradeEvent
+		if err := json.Unmarshal(envelope.Payload, &ev); err != nil {
+			return
+		}
+		select {
+		case s.Trades <- ev:
+		default:
+		}
+	case "balance":
+		var ev BalanceEvent
+		if err := json.Unmarshal(envelope.Payload, &ev); err != nil {
+			return
+		}
+		select {
+		case s.Balances <- ev:
+		default:
+		}
+	case "order":
+		var ev OrderUpdateEvent
+		if err := json.Unmarshal(envelope.Payload, &ev); err != nil {
+			return
+		}
+		select {
+		case s.OrderUpdates <- ev:
+		default:
+		}
+	}
+}